@@ -0,0 +1,102 @@
+package redblacktree
+
+import (
+	"github.com/koketama/redblacktree/internal/pkg"
+
+	"github.com/emirpasic/gods/utils"
+	"github.com/pkg/errors"
+)
+
+var _ ITree = itree{}
+
+// ITree is a persistent, immutable red-black tree. Put and Remove return a
+// new ITree value that shares every subtree untouched by the change with
+// the receiver, so a reference to an older ITree stays valid and requires
+// no locking.
+type ITree interface {
+	Put(key interface{}, value Value) ITree
+	Get(key interface{}) (values []Value, found bool)
+	Remove(key interface{}) ITree
+	Empty() bool
+	Size() int
+	Txn() *ImmutableTxn
+}
+
+type itree struct {
+	it pkg.ITree
+}
+
+// NewImmutable creates an empty, persistent red-black tree ordered by
+// comparator.
+func NewImmutable(comparator utils.Comparator) (ITree, error) {
+	if comparator == nil {
+		return nil, errors.New("comparator required")
+	}
+
+	return itree{it: pkg.NewImmutable(pkg.Comparator(comparator))}, nil
+}
+
+func (t itree) Put(key interface{}, value Value) ITree {
+	if key == nil || value == nil {
+		return t
+	}
+
+	return itree{it: t.it.Put(key, value)}
+}
+
+func (t itree) Get(key interface{}) (values []Value, found bool) {
+	if key == nil {
+		return nil, false
+	}
+
+	return t.it.Get(key)
+}
+
+func (t itree) Remove(key interface{}) ITree {
+	if key == nil {
+		return t
+	}
+
+	return itree{it: t.it.Remove(key)}
+}
+
+func (t itree) Empty() bool {
+	return t.it.Empty()
+}
+
+func (t itree) Size() int {
+	return t.it.Size()
+}
+
+func (t itree) Txn() *ImmutableTxn {
+	return &ImmutableTxn{txn: t.it.Txn()}
+}
+
+// ImmutableTxn batches Put/Remove calls against the ITree snapshot it was
+// opened from, producing one new ITree on Commit instead of one per call.
+type ImmutableTxn struct {
+	txn *pkg.Txn
+}
+
+// Put stages a value under key.
+func (tx *ImmutableTxn) Put(key interface{}, value Value) {
+	if key == nil || value == nil {
+		return
+	}
+
+	tx.txn.Put(key, value)
+}
+
+// Remove stages the removal of key and its values.
+func (tx *ImmutableTxn) Remove(key interface{}) {
+	if key == nil {
+		return
+	}
+
+	tx.txn.Remove(key)
+}
+
+// Commit returns the ITree reflecting every staged Put/Remove.
+func (tx *ImmutableTxn) Commit() ITree {
+	return itree{it: tx.txn.Commit()}
+}
@@ -0,0 +1,42 @@
+package redblacktree
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/emirpasic/gods/utils"
+)
+
+func TestImmutable(t *testing.T) {
+	v0, _ := NewImmutable(utils.IntComparator)
+
+	v1 := v0.Put(1, Entity("A"))
+	v2 := v1.Put(2, Entity("B"))
+	v3 := v2.Remove(1)
+
+	fmt.Println(v0.Size(), v1.Size(), v2.Size(), v3.Size())
+	fmt.Println(v1.Get(1))
+	fmt.Println(v3.Get(1))
+
+	txn := v2.Txn()
+	txn.Put(3, Entity("C"))
+	txn.Remove(2)
+	v4 := txn.Commit()
+
+	fmt.Println(v4.Get(2))
+	fmt.Println(v4.Get(3))
+}
+
+func TestSnapshot(t *testing.T) {
+	tree, _ := New(utils.IntComparator)
+
+	tree.Put(1, Entity("A"))
+	tree.Put(2, Entity("B"))
+
+	view, _ := tree.Snapshot()
+
+	tree.Put(3, Entity("C"))
+
+	fmt.Println(view.Size(), tree.Size())
+	fmt.Println(view.Get(3))
+}
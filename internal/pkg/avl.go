@@ -0,0 +1,526 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/emirpasic/gods/utils"
+)
+
+var _ Backend = (*avlTree)(nil)
+
+// avlNode is a node in an avlTree, with an explicit parent pointer so
+// successor/predecessor and the iterator can walk the tree without a stack.
+type avlNode struct {
+	key                 interface{}
+	values              []Value
+	left, right, parent *avlNode
+	height              int
+}
+
+// avlTree is a self-balancing binary search tree keeping every node's left
+// and right subtree heights within 1 of each other, which bounds its height
+// to O(log n) more tightly than a red-black tree does. That makes lookups a
+// little faster at the cost of more rotations per write.
+type avlTree struct {
+	root       *avlNode
+	comparator utils.Comparator
+	size       int
+}
+
+// NewAVL creates a Backend ordered by comparator, balanced as an AVL tree.
+func NewAVL(comparator utils.Comparator) Backend {
+	return &avlTree{comparator: comparator}
+}
+
+func avlHeight(n *avlNode) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+func avlBalance(n *avlNode) int {
+	if n == nil {
+		return 0
+	}
+
+	return avlHeight(n.left) - avlHeight(n.right)
+}
+
+func avlUpdateHeight(n *avlNode) {
+	h := avlHeight(n.left)
+	if r := avlHeight(n.right); r > h {
+		h = r
+	}
+
+	n.height = h + 1
+}
+
+func avlRotateLeft(n *avlNode) *avlNode {
+	r := n.right
+	n.right = r.left
+	if r.left != nil {
+		r.left.parent = n
+	}
+
+	r.parent = n.parent
+	r.left = n
+	n.parent = r
+
+	avlUpdateHeight(n)
+	avlUpdateHeight(r)
+
+	return r
+}
+
+func avlRotateRight(n *avlNode) *avlNode {
+	l := n.left
+	n.left = l.right
+	if l.right != nil {
+		l.right.parent = n
+	}
+
+	l.parent = n.parent
+	l.right = n
+	n.parent = l
+
+	avlUpdateHeight(n)
+	avlUpdateHeight(l)
+
+	return l
+}
+
+// avlRebalance restores the AVL invariant at n, which must already have
+// balanced children, and returns the subtree's new root.
+func avlRebalance(n *avlNode) *avlNode {
+	avlUpdateHeight(n)
+
+	switch balance := avlBalance(n); {
+	case balance > 1:
+		if avlBalance(n.left) < 0 {
+			n.left = avlRotateLeft(n.left)
+			n.left.parent = n
+		}
+
+		return avlRotateRight(n)
+	case balance < -1:
+		if avlBalance(n.right) > 0 {
+			n.right = avlRotateRight(n.right)
+			n.right.parent = n
+		}
+
+		return avlRotateLeft(n)
+	default:
+		return n
+	}
+}
+
+// avlRetrace walks from n up to the root, rebalancing each ancestor, and
+// re-links each rebalanced subtree into its parent.
+func (t *avlTree) avlRetrace(n *avlNode) {
+	for n != nil {
+		parent := n.parent
+		balanced := avlRebalance(n)
+		balanced.parent = parent
+
+		if parent == nil {
+			t.root = balanced
+		} else if parent.left == n {
+			parent.left = balanced
+		} else {
+			parent.right = balanced
+		}
+
+		n = parent
+	}
+}
+
+// Put appends value to key's slice, ignoring it if its ID is already present.
+func (t *avlTree) Put(key interface{}, value Value) {
+	if t.root == nil {
+		t.root = &avlNode{key: key, values: []Value{value}, height: 1}
+		t.size = 1
+		return
+	}
+
+	node := t.root
+	for {
+		cmp := t.comparator(key, node.key)
+		switch {
+		case cmp == 0:
+			for _, v := range node.values {
+				if v.ID() == value.ID() {
+					return
+				}
+			}
+
+			node.values = append(node.values, value)
+			return
+		case cmp < 0:
+			if node.left == nil {
+				node.left = &avlNode{key: key, values: []Value{value}, height: 1, parent: node}
+				t.size++
+				t.avlRetrace(node)
+				return
+			}
+
+			node = node.left
+		default:
+			if node.right == nil {
+				node.right = &avlNode{key: key, values: []Value{value}, height: 1, parent: node}
+				t.size++
+				t.avlRetrace(node)
+				return
+			}
+
+			node = node.right
+		}
+	}
+}
+
+func (t *avlTree) find(key interface{}) *avlNode {
+	node := t.root
+	for node != nil {
+		switch cmp := t.comparator(key, node.key); {
+		case cmp == 0:
+			return node
+		case cmp < 0:
+			node = node.left
+		default:
+			node = node.right
+		}
+	}
+
+	return nil
+}
+
+// Get returns the values stored under key, if any.
+func (t *avlTree) Get(key interface{}) (values []Value, found bool) {
+	node := t.find(key)
+	if node == nil {
+		return nil, false
+	}
+
+	return node.values, true
+}
+
+// Remove deletes key and all of its values.
+func (t *avlTree) Remove(key interface{}) {
+	node := t.find(key)
+	if node == nil {
+		return
+	}
+
+	if node.left != nil && node.right != nil {
+		succ := node.right
+		for succ.left != nil {
+			succ = succ.left
+		}
+
+		node.key, node.values = succ.key, succ.values
+		node = succ
+	}
+
+	child := node.left
+	if child == nil {
+		child = node.right
+	}
+
+	parent := node.parent
+	if child != nil {
+		child.parent = parent
+	}
+
+	switch {
+	case parent == nil:
+		t.root = child
+	case parent.left == node:
+		parent.left = child
+	default:
+		parent.right = child
+	}
+
+	t.size--
+	t.avlRetrace(parent)
+}
+
+// Empty reports whether the tree holds no keys.
+func (t *avlTree) Empty() bool {
+	return t.size == 0
+}
+
+// Size returns the number of distinct keys in the tree.
+func (t *avlTree) Size() int {
+	return t.size
+}
+
+func avlLeftmost(n *avlNode) *avlNode {
+	if n == nil {
+		return nil
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+func avlRightmost(n *avlNode) *avlNode {
+	if n == nil {
+		return nil
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n
+}
+
+func avlSuccessor(n *avlNode) *avlNode {
+	if n.right != nil {
+		return avlLeftmost(n.right)
+	}
+
+	for n.parent != nil && n == n.parent.right {
+		n = n.parent
+	}
+
+	return n.parent
+}
+
+func avlPredecessor(n *avlNode) *avlNode {
+	if n.left != nil {
+		return avlRightmost(n.left)
+	}
+
+	for n.parent != nil && n == n.parent.left {
+		n = n.parent
+	}
+
+	return n.parent
+}
+
+func avlNodeKeyValues(n *avlNode) (key interface{}, values []Value) {
+	if n == nil {
+		return nil, nil
+	}
+
+	return n.key, n.values
+}
+
+// Left returns the smallest key in the tree and its values.
+func (t *avlTree) Left() (key interface{}, values []Value) {
+	return avlNodeKeyValues(avlLeftmost(t.root))
+}
+
+// Right returns the largest key in the tree and its values.
+func (t *avlTree) Right() (key interface{}, values []Value) {
+	return avlNodeKeyValues(avlRightmost(t.root))
+}
+
+// floorNode returns the node holding the largest key <= key.
+func (t *avlTree) floorNode(key interface{}) *avlNode {
+	var result *avlNode
+	node := t.root
+	for node != nil {
+		switch cmp := t.comparator(key, node.key); {
+		case cmp == 0:
+			return node
+		case cmp < 0:
+			node = node.left
+		default:
+			result = node
+			node = node.right
+		}
+	}
+
+	return result
+}
+
+// ceilingNode returns the node holding the smallest key >= key.
+func (t *avlTree) ceilingNode(key interface{}) *avlNode {
+	var result *avlNode
+	node := t.root
+	for node != nil {
+		switch cmp := t.comparator(key, node.key); {
+		case cmp == 0:
+			return node
+		case cmp < 0:
+			result = node
+			node = node.left
+		default:
+			node = node.right
+		}
+	}
+
+	return result
+}
+
+// Floor returns the largest key <= key, and its values.
+func (t *avlTree) Floor(key interface{}) (k interface{}, values []Value, found bool) {
+	node := t.floorNode(key)
+	if node == nil {
+		return nil, nil, false
+	}
+
+	k, values = avlNodeKeyValues(node)
+	return k, values, true
+}
+
+// Ceiling returns the smallest key >= key, and its values.
+func (t *avlTree) Ceiling(key interface{}) (k interface{}, values []Value, found bool) {
+	node := t.ceilingNode(key)
+	if node == nil {
+		return nil, nil, false
+	}
+
+	k, values = avlNodeKeyValues(node)
+	return k, values, true
+}
+
+// Lower returns the largest key strictly less than key, and its values.
+func (t *avlTree) Lower(key interface{}) (k interface{}, values []Value, found bool) {
+	node := t.floorNode(key)
+	if node == nil {
+		return nil, nil, false
+	}
+
+	if t.comparator(node.key, key) == 0 {
+		node = avlPredecessor(node)
+		if node == nil {
+			return nil, nil, false
+		}
+	}
+
+	k, values = avlNodeKeyValues(node)
+	return k, values, true
+}
+
+// Higher returns the smallest key strictly greater than key, and its values.
+func (t *avlTree) Higher(key interface{}) (k interface{}, values []Value, found bool) {
+	node := t.ceilingNode(key)
+	if node == nil {
+		return nil, nil, false
+	}
+
+	if t.comparator(node.key, key) == 0 {
+		node = avlSuccessor(node)
+		if node == nil {
+			return nil, nil, false
+		}
+	}
+
+	k, values = avlNodeKeyValues(node)
+	return k, values, true
+}
+
+// Range walks the keys between lo and hi, inclusive of both bounds when
+// inclusive is true and exclusive of both when it is false, calling fn for
+// each in ascending order. Range stops early if fn returns false.
+func (t *avlTree) Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool) {
+	var node *avlNode
+	if inclusive {
+		node = t.ceilingNode(lo)
+	} else {
+		node = t.ceilingNode(lo)
+		if node != nil && t.comparator(node.key, lo) == 0 {
+			node = avlSuccessor(node)
+		}
+	}
+
+	for node != nil {
+		cmp := t.comparator(node.key, hi)
+		if cmp > 0 || (cmp == 0 && !inclusive) {
+			return
+		}
+
+		if !fn(node.key, node.values) {
+			return
+		}
+
+		node = avlSuccessor(node)
+	}
+}
+
+// String renders the tree topology, for debugging.
+func (t *avlTree) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("AVLTree\n")
+
+	var write func(n *avlNode, depth int)
+	write = func(n *avlNode, depth int) {
+		if n == nil {
+			return
+		}
+
+		write(n.right, depth+1)
+		fmt.Fprintf(&buf, "%*s%v\n", depth*4, "", n.key)
+		write(n.left, depth+1)
+	}
+
+	write(t.root, 0)
+	return buf.String()
+}
+
+// Iterator returns an Iterator positioned before the smallest key.
+func (t *avlTree) Iterator() Iterator {
+	return &avlIterator{tree: t}
+}
+
+// avlIterator is a stateful, forward, in-order iterator over an avlTree.
+type avlIterator struct {
+	tree    *avlTree
+	node    *avlNode
+	started bool
+	queued  *avlNode
+	seeked  bool
+}
+
+// Next advances the iterator and reports whether a key is available. After a
+// call to Seek, Next lands on the sought key itself before continuing the
+// walk.
+func (it *avlIterator) Next() bool {
+	if it.seeked {
+		it.seeked = false
+		it.started = true
+		it.node = it.queued
+		return it.node != nil
+	}
+
+	if !it.started {
+		it.started = true
+		it.node = avlLeftmost(it.tree.root)
+		return it.node != nil
+	}
+
+	if it.node == nil {
+		return false
+	}
+
+	it.node = avlSuccessor(it.node)
+	return it.node != nil
+}
+
+// Seek positions the iterator so that the next call to Next lands on the
+// first key >= key, in O(log n) instead of scanning forward from Min.
+func (it *avlIterator) Seek(key interface{}) bool {
+	it.queued = it.tree.ceilingNode(key)
+	it.seeked = true
+	return it.queued != nil
+}
+
+// Key returns the current key. Valid only after a call to Next that
+// returned true.
+func (it *avlIterator) Key() interface{} {
+	return it.node.key
+}
+
+// Values returns the current key's values. Valid only after a call to Next
+// that returned true.
+func (it *avlIterator) Values() []Value {
+	return it.node.values
+}
@@ -0,0 +1,50 @@
+package pkg
+
+// Backend is the balanced binary search tree behind a Tree. Put/Get/Remove
+// and the ordered navigation methods must all honor the same
+// multi-value-per-key semantics: Put appends to key's slice unless
+// value.ID() already appears in it.
+type Backend interface {
+	Put(key interface{}, value Value)
+	Get(key interface{}) (values []Value, found bool)
+	Remove(key interface{})
+	Empty() bool
+	Size() int
+	Left() (key interface{}, values []Value)
+	Right() (key interface{}, values []Value)
+	Floor(key interface{}) (k interface{}, values []Value, found bool)
+	Ceiling(key interface{}) (k interface{}, values []Value, found bool)
+	Lower(key interface{}) (k interface{}, values []Value, found bool)
+	Higher(key interface{}) (k interface{}, values []Value, found bool)
+	Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool)
+	String() string
+	Iterator() Iterator
+}
+
+// Iterator is a stateful, forward, in-order iterator over a Backend.
+type Iterator interface {
+	Next() bool
+	Seek(key interface{}) bool
+	Key() interface{}
+	Values() []Value
+}
+
+// PopLeft removes and returns the smallest key in b and its values.
+func PopLeft(b Backend) (key interface{}, values []Value) {
+	key, values = b.Left()
+	if key != nil {
+		b.Remove(key)
+	}
+
+	return
+}
+
+// PopRight removes and returns the largest key in b and its values.
+func PopRight(b Backend) (key interface{}, values []Value) {
+	key, values = b.Right()
+	if key != nil {
+		b.Remove(key)
+	}
+
+	return
+}
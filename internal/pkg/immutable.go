@@ -0,0 +1,377 @@
+package pkg
+
+// Comparator compares two keys the same way utils.Comparator from
+// github.com/emirpasic/gods does: negative if a < b, zero if equal,
+// positive if a > b. Declared locally (rather than depending on gods) since
+// the immutable tree below is a from-scratch implementation.
+type Comparator func(a, b interface{}) int
+
+type rbColor bool
+
+const (
+	red   rbColor = true
+	black rbColor = false
+)
+
+// inode is a node of a persistent, left-leaning red-black tree. Mutating
+// operations never change an inode in place: every node on the path from
+// the root to the change is cloned and rebalanced on the way back up, while
+// untouched subtrees are shared by pointer with the previous version.
+type inode struct {
+	key         interface{}
+	values      []Value
+	left, right *inode
+	color       rbColor
+}
+
+func (n *inode) clone() *inode {
+	c := *n
+	return &c
+}
+
+func isRed(n *inode) bool {
+	return n != nil && n.color == red
+}
+
+func rotateLeft(n *inode) *inode {
+	x := n.right.clone()
+	n = n.clone()
+	n.right = x.left
+	x.left = n
+	x.color = n.color
+	n.color = red
+	return x
+}
+
+func rotateRight(n *inode) *inode {
+	x := n.left.clone()
+	n = n.clone()
+	n.left = x.right
+	x.right = n
+	x.color = n.color
+	n.color = red
+	return x
+}
+
+// flipColors clones n and both of its children before flipping their
+// colors, so the (possibly shared) children of the previous version are
+// left untouched.
+func flipColors(n *inode) *inode {
+	n = n.clone()
+	n.left = n.left.clone()
+	n.right = n.right.clone()
+	n.color = !n.color
+	n.left.color = !n.left.color
+	n.right.color = !n.right.color
+	return n
+}
+
+func balance(n *inode) *inode {
+	if isRed(n.right) && !isRed(n.left) {
+		n = rotateLeft(n)
+	}
+	if isRed(n.left) && isRed(n.left.left) {
+		n = rotateRight(n)
+	}
+	if isRed(n.left) && isRed(n.right) {
+		n = flipColors(n)
+	}
+	return n
+}
+
+// iput returns a tree with key/value inserted, and 1 if key is new, 0 if it
+// already existed (whether or not value's ID was already present).
+func iput(n *inode, comparator Comparator, key interface{}, value Value) (*inode, int) {
+	if n == nil {
+		return &inode{key: key, values: []Value{value}, color: red}, 1
+	}
+
+	n = n.clone()
+	delta := 0
+
+	switch cmp := comparator(key, n.key); {
+	case cmp < 0:
+		n.left, delta = iput(n.left, comparator, key, value)
+	case cmp > 0:
+		n.right, delta = iput(n.right, comparator, key, value)
+	default:
+		dup := false
+		for _, v := range n.values {
+			if v.ID() == value.ID() {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			n.values = append(append([]Value{}, n.values...), value)
+		}
+	}
+
+	return balance(n), delta
+}
+
+func iget(n *inode, comparator Comparator, key interface{}) (values []Value, found bool) {
+	for n != nil {
+		switch cmp := comparator(key, n.key); {
+		case cmp < 0:
+			n = n.left
+		case cmp > 0:
+			n = n.right
+		default:
+			return n.values, true
+		}
+	}
+
+	return nil, false
+}
+
+func imin(n *inode) *inode {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func moveRedLeft(h *inode) *inode {
+	h = flipColors(h)
+	if isRed(h.right.left) {
+		h.right = rotateRight(h.right)
+		h = rotateLeft(h)
+		h = flipColors(h)
+	}
+	return h
+}
+
+func moveRedRight(h *inode) *inode {
+	h = flipColors(h)
+	if isRed(h.left.left) {
+		h = rotateRight(h)
+		h = flipColors(h)
+	}
+	return h
+}
+
+func ideleteMin(h *inode) *inode {
+	if h.left == nil {
+		return nil
+	}
+
+	if !isRed(h.left) && !isRed(h.left.left) {
+		h = moveRedLeft(h)
+	}
+
+	h = h.clone()
+	h.left = ideleteMin(h.left)
+
+	return balance(h)
+}
+
+func idelete(h *inode, comparator Comparator, key interface{}) *inode {
+	if comparator(key, h.key) < 0 {
+		if !isRed(h.left) && !isRed(h.left.left) {
+			h = moveRedLeft(h)
+		}
+
+		h = h.clone()
+		h.left = idelete(h.left, comparator, key)
+	} else {
+		if isRed(h.left) {
+			h = rotateRight(h)
+		}
+
+		if comparator(key, h.key) == 0 && h.right == nil {
+			return nil
+		}
+
+		if !isRed(h.right) && !isRed(h.right.left) {
+			h = moveRedRight(h)
+		}
+
+		h = h.clone()
+		if comparator(key, h.key) == 0 {
+			m := imin(h.right)
+			h.key = m.key
+			h.values = m.values
+			h.right = ideleteMin(h.right)
+		} else {
+			h.right = idelete(h.right, comparator, key)
+		}
+	}
+
+	return balance(h)
+}
+
+// ITree is a persistent, immutable red-black tree. Put and Remove return a
+// new ITree value; the receiver is left untouched and keeps sharing every
+// subtree the change didn't touch, so old and new versions can coexist
+// with no locking between them.
+type ITree struct {
+	root       *inode
+	comparator Comparator
+	size       int
+}
+
+// NewImmutable creates an empty ITree ordered by comparator.
+func NewImmutable(comparator Comparator) ITree {
+	return ITree{comparator: comparator}
+}
+
+// Put returns a new ITree with value appended to key's slice.
+func (t ITree) Put(key interface{}, value Value) ITree {
+	root, delta := iput(t.root, t.comparator, key, value)
+	root.color = black
+	return ITree{root: root, comparator: t.comparator, size: t.size + delta}
+}
+
+// Remove returns a new ITree with key and its values removed.
+func (t ITree) Remove(key interface{}) ITree {
+	if _, found := t.Get(key); !found {
+		return t
+	}
+
+	root := t.root
+	if !isRed(root.left) && !isRed(root.right) {
+		root = root.clone()
+		root.color = red
+	}
+
+	root = idelete(root, t.comparator, key)
+	if root != nil {
+		root = root.clone()
+		root.color = black
+	}
+
+	return ITree{root: root, comparator: t.comparator, size: t.size - 1}
+}
+
+// Get returns the values stored under key, if any.
+func (t ITree) Get(key interface{}) (values []Value, found bool) {
+	return iget(t.root, t.comparator, key)
+}
+
+// Empty reports whether the tree holds no keys.
+func (t ITree) Empty() bool {
+	return t.root == nil
+}
+
+// Size returns the number of distinct keys in the tree.
+func (t ITree) Size() int {
+	return t.size
+}
+
+// Min returns the smallest key in the tree and its values.
+func (t ITree) Min() (key interface{}, values []Value) {
+	n := t.root
+	if n == nil {
+		return nil, nil
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n.key, n.values
+}
+
+// Max returns the largest key in the tree and its values.
+func (t ITree) Max() (key interface{}, values []Value) {
+	n := t.root
+	if n == nil {
+		return nil, nil
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n.key, n.values
+}
+
+// Iterator returns an Iterator positioned before the smallest key.
+func (t ITree) Iterator() Iterator {
+	return newInodeIterator(t.root, t.comparator, false)
+}
+
+// ReverseIterator returns an Iterator positioned after the largest key,
+// walking keys in descending order.
+func (t ITree) ReverseIterator() Iterator {
+	return newInodeIterator(t.root, t.comparator, true)
+}
+
+// Range walks the keys between lo and hi, inclusive of both bounds when
+// inclusive is true and exclusive of both when it is false, calling fn for
+// each in ascending order. Range stops early if fn returns false.
+func (t ITree) Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool) {
+	it := newInodeIterator(t.root, t.comparator, false)
+	if !it.Seek(lo) {
+		return
+	}
+
+	skippedLo := false
+	for it.Next() {
+		if !inclusive && !skippedLo && t.comparator(it.Key(), lo) == 0 {
+			skippedLo = true
+			continue
+		}
+		skippedLo = true
+
+		cmp := t.comparator(it.Key(), hi)
+		if cmp > 0 || (cmp == 0 && !inclusive) {
+			return
+		}
+
+		if !fn(it.Key(), it.Values()) {
+			return
+		}
+	}
+}
+
+// Txn opens a transaction against this version of the tree. Commit yields a
+// single new ITree reflecting every Put/Remove applied to the Txn, instead
+// of allocating one new root per call.
+func (t ITree) Txn() *Txn {
+	return &Txn{comparator: t.comparator, root: t.root, size: t.size}
+}
+
+// Txn batches Put/Remove operations against an ITree's root, producing a
+// new ITree only when Commit is called.
+type Txn struct {
+	comparator Comparator
+	root       *inode
+	size       int
+}
+
+// Put stages a value under key.
+func (tx *Txn) Put(key interface{}, value Value) {
+	root, delta := iput(tx.root, tx.comparator, key, value)
+	root.color = black
+	tx.root = root
+	tx.size += delta
+}
+
+// Remove stages the removal of key and its values.
+func (tx *Txn) Remove(key interface{}) {
+	if _, found := iget(tx.root, tx.comparator, key); !found {
+		return
+	}
+
+	root := tx.root
+	if !isRed(root.left) && !isRed(root.right) {
+		root = root.clone()
+		root.color = red
+	}
+
+	root = idelete(root, tx.comparator, key)
+	if root != nil {
+		root = root.clone()
+		root.color = black
+	}
+
+	tx.root = root
+	tx.size--
+}
+
+// Commit returns the ITree reflecting every staged Put/Remove.
+func (tx *Txn) Commit() ITree {
+	return ITree{root: tx.root, comparator: tx.comparator, size: tx.size}
+}
@@ -0,0 +1,94 @@
+package pkg
+
+var _ Iterator = (*inodeIterator)(nil)
+
+// inodeIterator is a stateful, in-order (or reverse in-order) iterator over
+// an ITree's inode tree. inode has no parent pointer, so unlike rbIterator
+// it walks with an explicit stack of ancestors rather than climbing back up
+// the tree.
+type inodeIterator struct {
+	root       *inode
+	comparator Comparator
+	reverse    bool
+	stack      []*inode
+	current    *inode
+}
+
+func newInodeIterator(root *inode, comparator Comparator, reverse bool) *inodeIterator {
+	it := &inodeIterator{root: root, comparator: comparator, reverse: reverse}
+	it.pushSpine(root)
+	return it
+}
+
+// pushSpine pushes n and its descendants down the traversal direction (left
+// for forward, right for reverse) onto the stack.
+func (it *inodeIterator) pushSpine(n *inode) {
+	for n != nil {
+		it.stack = append(it.stack, n)
+		if it.reverse {
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+}
+
+// Next advances the iterator and reports whether a key is available.
+func (it *inodeIterator) Next() bool {
+	if len(it.stack) == 0 {
+		it.current = nil
+		return false
+	}
+
+	n := it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	it.current = n
+
+	if it.reverse {
+		it.pushSpine(n.left)
+	} else {
+		it.pushSpine(n.right)
+	}
+
+	return true
+}
+
+// Seek positions the iterator so that the next call to Next lands on the
+// first key >= key for a forward iterator, or the last key <= key for a
+// reverse one, in O(log n) instead of scanning from the end.
+func (it *inodeIterator) Seek(key interface{}) bool {
+	it.stack = it.stack[:0]
+
+	for n := it.root; n != nil; {
+		cmp := it.comparator(key, n.key)
+		if it.reverse {
+			if cmp >= 0 {
+				it.stack = append(it.stack, n)
+				n = n.right
+			} else {
+				n = n.left
+			}
+		} else {
+			if cmp <= 0 {
+				it.stack = append(it.stack, n)
+				n = n.left
+			} else {
+				n = n.right
+			}
+		}
+	}
+
+	return len(it.stack) > 0
+}
+
+// Key returns the current key. Valid only after a call to Next that
+// returned true.
+func (it *inodeIterator) Key() interface{} {
+	return it.current.key
+}
+
+// Values returns the current key's values. Valid only after a call to Next
+// that returned true.
+func (it *inodeIterator) Values() []Value {
+	return it.current.values
+}
@@ -0,0 +1,64 @@
+package pkg
+
+import godsrbt "github.com/emirpasic/gods/trees/redblacktree"
+
+var _ Iterator = (*rbIterator)(nil)
+
+// rbIterator is a stateful, forward, in-order iterator over a Tree.
+type rbIterator struct {
+	tree    *Tree
+	node    *godsrbt.Node
+	started bool
+	queued  *godsrbt.Node
+	seeked  bool
+}
+
+// Iterator returns an Iterator positioned before the smallest key.
+func (t *Tree) Iterator() Iterator {
+	return &rbIterator{tree: t}
+}
+
+// Next advances the iterator and reports whether a key is available. After a
+// call to Seek, Next lands on the sought key itself before continuing the
+// walk.
+func (it *rbIterator) Next() bool {
+	if it.seeked {
+		it.seeked = false
+		it.started = true
+		it.node = it.queued
+		return it.node != nil
+	}
+
+	if !it.started {
+		it.started = true
+		it.node = it.tree.rbt.Left()
+		return it.node != nil
+	}
+
+	if it.node == nil {
+		return false
+	}
+
+	it.node = successor(it.node)
+	return it.node != nil
+}
+
+// Seek positions the iterator so that the next call to Next lands on the
+// first key >= key, in O(log n) instead of scanning forward from Min.
+func (it *rbIterator) Seek(key interface{}) bool {
+	it.queued, _ = it.tree.rbt.Ceiling(key)
+	it.seeked = true
+	return it.queued != nil
+}
+
+// Key returns the current key. Valid only after a call to Next that
+// returned true.
+func (it *rbIterator) Key() interface{} {
+	return it.node.Key
+}
+
+// Values returns the current key's values. Valid only after a call to Next
+// that returned true.
+func (it *rbIterator) Values() []Value {
+	return it.node.Value.([]Value)
+}
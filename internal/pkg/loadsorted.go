@@ -0,0 +1,65 @@
+package pkg
+
+// Entry is one key and its values, as consumed by LoadSorted.
+type Entry struct {
+	Key    interface{}
+	Values []Value
+}
+
+// LoadSorted builds a height-balanced tree directly from entries, which
+// must already be sorted ascending by comparator, in O(n) rather than the
+// O(n log n) that n sequential Puts would cost.
+func LoadSorted(comparator Comparator, entries []Entry) ITree {
+	root := buildBalanced(entries, 0, len(entries)-1, 0, redLevel(len(entries)))
+	if root != nil {
+		root = root.clone()
+		root.color = black
+	}
+
+	return ITree{
+		root:       root,
+		comparator: comparator,
+		size:       len(entries),
+	}
+}
+
+// redLevel returns the depth (root at depth 0) of the deepest, incomplete
+// level of the otherwise-perfect binary tree over n entries. When n fills
+// every level (n = 2^k - 1) it returns k, one past the deepest level, so
+// no node is red. buildBalanced colors exactly that level red and nothing
+// else, which
+// keeps every root-to-nil path's black-height equal since red nodes don't
+// contribute to it.
+func redLevel(n int) int {
+	level := 0
+	for m := n - 1; m >= 0; m = m/2 - 1 {
+		level++
+	}
+	return level
+}
+
+// buildBalanced lays entries out as a complete binary tree and colors the
+// deepest level red per redLevel, then folds each node through balance on
+// the way back up so any red link that ended up right-leaning (or any
+// resulting 4-node) is rotated/split into valid left-leaning form. The
+// caller is responsible for forcing the returned root black.
+func buildBalanced(entries []Entry, lo, hi, level, redLvl int) *inode {
+	if lo > hi {
+		return nil
+	}
+
+	mid := (lo + hi) / 2
+
+	n := &inode{
+		key:    entries[mid].Key,
+		values: entries[mid].Values,
+		color:  black,
+		left:   buildBalanced(entries, lo, mid-1, level+1, redLvl),
+		right:  buildBalanced(entries, mid+1, hi, level+1, redLvl),
+	}
+	if level == redLvl {
+		n.color = red
+	}
+
+	return balance(n)
+}
@@ -0,0 +1,130 @@
+package pkg
+
+import godsrbt "github.com/emirpasic/gods/trees/redblacktree"
+
+// Floor returns the largest key <= key, and its values.
+func (t *Tree) Floor(key interface{}) (k interface{}, values []Value, found bool) {
+	node, found := t.rbt.Floor(key)
+	if !found {
+		return nil, nil, false
+	}
+
+	k, values = nodeKeyValues(node)
+	return k, values, true
+}
+
+// Ceiling returns the smallest key >= key, and its values.
+func (t *Tree) Ceiling(key interface{}) (k interface{}, values []Value, found bool) {
+	node, found := t.rbt.Ceiling(key)
+	if !found {
+		return nil, nil, false
+	}
+
+	k, values = nodeKeyValues(node)
+	return k, values, true
+}
+
+// Lower returns the largest key strictly less than key, and its values.
+func (t *Tree) Lower(key interface{}) (k interface{}, values []Value, found bool) {
+	node, found := t.rbt.Floor(key)
+	if !found {
+		return nil, nil, false
+	}
+
+	if t.rbt.Comparator(node.Key, key) == 0 {
+		node = predecessor(node)
+		if node == nil {
+			return nil, nil, false
+		}
+	}
+
+	k, values = nodeKeyValues(node)
+	return k, values, true
+}
+
+// Higher returns the smallest key strictly greater than key, and its values.
+func (t *Tree) Higher(key interface{}) (k interface{}, values []Value, found bool) {
+	node, found := t.rbt.Ceiling(key)
+	if !found {
+		return nil, nil, false
+	}
+
+	if t.rbt.Comparator(node.Key, key) == 0 {
+		node = successor(node)
+		if node == nil {
+			return nil, nil, false
+		}
+	}
+
+	k, values = nodeKeyValues(node)
+	return k, values, true
+}
+
+// Range walks the keys between lo and hi, inclusive of both bounds when
+// inclusive is true and exclusive of both when it is false, calling fn for
+// each in ascending order. Range stops early if fn returns false. No
+// intermediate slice of keys/values is materialized.
+func (t *Tree) Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool) {
+	var node *godsrbt.Node
+	var found bool
+
+	if inclusive {
+		node, found = t.rbt.Ceiling(lo)
+	} else {
+		node, found = t.rbt.Ceiling(lo)
+		if found && t.rbt.Comparator(node.Key, lo) == 0 {
+			node = successor(node)
+			found = node != nil
+		}
+	}
+
+	for found {
+		cmp := t.rbt.Comparator(node.Key, hi)
+		if cmp > 0 || (cmp == 0 && !inclusive) {
+			return
+		}
+
+		if !fn(node.Key, node.Value.([]Value)) {
+			return
+		}
+
+		node = successor(node)
+		found = node != nil
+	}
+}
+
+// successor returns the in-order successor of node using parent pointers.
+func successor(node *godsrbt.Node) *godsrbt.Node {
+	if node.Right != nil {
+		node = node.Right
+		for node.Left != nil {
+			node = node.Left
+		}
+
+		return node
+	}
+
+	for node.Parent != nil && node == node.Parent.Right {
+		node = node.Parent
+	}
+
+	return node.Parent
+}
+
+// predecessor returns the in-order predecessor of node using parent pointers.
+func predecessor(node *godsrbt.Node) *godsrbt.Node {
+	if node.Left != nil {
+		node = node.Left
+		for node.Right != nil {
+			node = node.Right
+		}
+
+		return node
+	}
+
+	for node.Parent != nil && node == node.Parent.Left {
+		node = node.Parent
+	}
+
+	return node.Parent
+}
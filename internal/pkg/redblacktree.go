@@ -0,0 +1,87 @@
+// Package pkg adapts github.com/emirpasic/gods/trees/redblacktree so that
+// each key can hold more than one Value, deduplicated by Value.ID().
+package pkg
+
+import (
+	godsrbt "github.com/emirpasic/gods/trees/redblacktree"
+	"github.com/emirpasic/gods/utils"
+)
+
+// Value is the element type stored under a key. Two values with the same ID
+// under the same key are considered duplicates; the second Put is a no-op.
+type Value interface {
+	ID() string
+}
+
+var _ Backend = (*Tree)(nil)
+
+// Tree is a red-black tree keyed by an arbitrary comparable type, with each
+// key holding a slice of Value. It's the default Backend.
+type Tree struct {
+	rbt *godsrbt.Tree
+}
+
+// NewWith creates a Tree ordered by comparator.
+func NewWith(comparator utils.Comparator) *Tree {
+	return &Tree{rbt: godsrbt.NewWith(comparator)}
+}
+
+// Put appends value to key's slice, ignoring it if its ID is already present.
+func (t *Tree) Put(key interface{}, value Value) {
+	values, _ := t.Get(key)
+	for _, v := range values {
+		if v.ID() == value.ID() {
+			return
+		}
+	}
+
+	t.rbt.Put(key, append(values, value))
+}
+
+// Get returns the values stored under key, if any.
+func (t *Tree) Get(key interface{}) (values []Value, found bool) {
+	v, found := t.rbt.Get(key)
+	if !found {
+		return nil, false
+	}
+
+	return v.([]Value), true
+}
+
+// Remove deletes key and all of its values.
+func (t *Tree) Remove(key interface{}) {
+	t.rbt.Remove(key)
+}
+
+// Empty reports whether the tree holds no keys.
+func (t *Tree) Empty() bool {
+	return t.rbt.Empty()
+}
+
+// Size returns the number of distinct keys in the tree.
+func (t *Tree) Size() int {
+	return t.rbt.Size()
+}
+
+// Left returns the smallest key in the tree and its values.
+func (t *Tree) Left() (key interface{}, values []Value) {
+	return nodeKeyValues(t.rbt.Left())
+}
+
+// Right returns the largest key in the tree and its values.
+func (t *Tree) Right() (key interface{}, values []Value) {
+	return nodeKeyValues(t.rbt.Right())
+}
+
+// String renders the tree topology, for debugging.
+func (t *Tree) String() string {
+	return t.rbt.String()
+}
+
+func nodeKeyValues(node *godsrbt.Node) (key interface{}, values []Value) {
+	if node == nil {
+		return nil, nil
+	}
+
+	return node.Key, node.Value.([]Value)
+}
@@ -53,3 +53,27 @@ func TestRBT(t *testing.T) {
 	}
 	fmt.Println("----------------------")
 }
+
+func TestNavigate(t *testing.T) {
+	tree := NewWith(utils.IntComparator)
+
+	tree.Put(1, Entity("A"))
+	tree.Put(3, Entity("B"))
+	tree.Put(5, Entity("C"))
+
+	fmt.Println(tree.Floor(4))
+	fmt.Println(tree.Ceiling(4))
+	fmt.Println(tree.Lower(3))
+	fmt.Println(tree.Higher(3))
+
+	it := tree.Iterator()
+	it.Seek(3)
+	for it.Next() {
+		fmt.Println(it.Key(), it.Values())
+	}
+
+	tree.Range(1, 5, true, func(k interface{}, v []Value) bool {
+		fmt.Println(k, v)
+		return true
+	})
+}
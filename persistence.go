@@ -0,0 +1,286 @@
+package redblacktree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+
+	"github.com/koketama/redblacktree/internal/pkg"
+
+	"github.com/emirpasic/gods/utils"
+	"github.com/pkg/errors"
+)
+
+// Encoding selects the wire format used by Marshal and Unmarshal.
+type Encoding int
+
+const (
+	// EncodingBinary is a compact, length-prefixed format: each record is a
+	// varint key length, the gob-encoded key, a varint values length, and
+	// the gob-encoded values. Concrete key/value types must be registered
+	// with Register first.
+	EncodingBinary Encoding = iota
+	// EncodingJSON is a streaming JSON array of {"key":...,"values":[...]}
+	// records. Unmarshal needs a ValueDecoder since JSON carries no type
+	// information to reconstruct a Value from, and decodes keys with
+	// encoding/json's generic rules (e.g. a numeric key comes back as
+	// float64, not its original type) since it has the same problem.
+	EncodingJSON
+)
+
+// Register records sample's concrete type so the binary encoding can carry
+// it as a key or Value despite the tree only ever seeing interface{} and
+// Value. Call it once per concrete type before using EncodingBinary.
+func Register(sample interface{}) {
+	gob.Register(sample)
+}
+
+// ValueDecoder reconstructs a Value from its JSON encoding. Only consulted
+// by Unmarshal when reading EncodingJSON.
+type ValueDecoder func(raw json.RawMessage) (Value, error)
+
+type jsonRecord struct {
+	Key    interface{} `json:"key"`
+	Values []Value     `json:"values"`
+}
+
+type jsonRawRecord struct {
+	Key    json.RawMessage   `json:"key"`
+	Values []json.RawMessage `json:"values"`
+}
+
+// Marshal writes every key and its values to w, in ascending key order,
+// using encoding.
+func (t *tree) Marshal(w io.Writer, encoding Encoding) error {
+	t.RLock()
+	defer t.RUnlock()
+
+	switch encoding {
+	case EncodingBinary:
+		return marshalBinary(w, t.rbt)
+	case EncodingJSON:
+		return marshalJSON(w, t.rbt)
+	default:
+		return errors.Errorf("redblacktree: unknown encoding %d", encoding)
+	}
+}
+
+func marshalBinary(w io.Writer, rbt pkg.Backend) error {
+	scratch := make([]byte, binary.MaxVarintLen64)
+
+	it := rbt.Iterator()
+	for it.Next() {
+		key := it.Key()
+		keyBytes, err := gobEncode(&key)
+		if err != nil {
+			return err
+		}
+
+		values := it.Values()
+		valuesBytes, err := gobEncode(values)
+		if err != nil {
+			return err
+		}
+
+		if err := writeChunk(w, scratch, keyBytes); err != nil {
+			return err
+		}
+
+		if err := writeChunk(w, scratch, valuesBytes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeChunk(w io.Writer, scratch, data []byte) error {
+	n := binary.PutUvarint(scratch, uint64(len(data)))
+	if _, err := w.Write(scratch[:n]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(data)
+	return err
+}
+
+// gobEncode encodes v as-is. Pass a *interface{} for a key, whose concrete
+// type varies per tree and so must cross the wire as a registered
+// interface value; pass a []Value directly, since its element type
+// (Value, an interface) only needs each element's concrete type
+// registered, not the slice type itself.
+func gobEncode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func marshalJSON(w io.Writer, rbt pkg.Backend) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	it := rbt.Iterator()
+	first := true
+	for it.Next() {
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		b, err := json.Marshal(jsonRecord{Key: it.Key(), Values: it.Values()})
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// Unmarshal reads a tree written by Marshal and returns a new Tree ordered
+// by comparator. decodeValue is only used when reading EncodingJSON; pass
+// nil when reading EncodingBinary.
+func Unmarshal(r io.Reader, comparator utils.Comparator, encoding Encoding, decodeValue ValueDecoder) (Tree, error) {
+	t, err := New(comparator)
+	if err != nil {
+		return nil, err
+	}
+
+	switch encoding {
+	case EncodingBinary:
+		err = unmarshalBinary(r, t)
+	case EncodingJSON:
+		err = unmarshalJSON(r, t, decodeValue)
+	default:
+		err = errors.Errorf("redblacktree: unknown encoding %d", encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func unmarshalBinary(r io.Reader, t Tree) error {
+	br := bufio.NewReader(r)
+
+	for {
+		keyBytes, err := readChunk(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		valuesBytes, err := readChunk(br)
+		if err != nil {
+			return err
+		}
+
+		var key interface{}
+		if err := gobDecode(keyBytes, &key); err != nil {
+			return err
+		}
+
+		var values []Value
+		if err := gobDecode(valuesBytes, &values); err != nil {
+			return err
+		}
+
+		for _, v := range values {
+			t.Put(key, v)
+		}
+	}
+}
+
+func readChunk(br *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func gobDecode(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func unmarshalJSON(r io.Reader, t Tree, decodeValue ValueDecoder) error {
+	if decodeValue == nil {
+		return errors.New("redblacktree: decodeValue required for EncodingJSON")
+	}
+
+	dec := json.NewDecoder(r)
+
+	if _, err := dec.Token(); err != nil { // consume the opening '['
+		return err
+	}
+
+	for dec.More() {
+		var raw jsonRawRecord
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+
+		var key interface{}
+		if err := json.Unmarshal(raw.Key, &key); err != nil {
+			return err
+		}
+
+		for _, rawValue := range raw.Values {
+			value, err := decodeValue(rawValue)
+			if err != nil {
+				return err
+			}
+
+			t.Put(key, value)
+		}
+	}
+
+	_, err := dec.Token() // consume the closing ']'
+	return err
+}
+
+// LoadSorted builds a height-balanced, persistent tree from iter, which
+// must yield entries already sorted ascending by comparator, in O(n)
+// instead of the O(n log n) that n sequential Puts would cost — the shape
+// Unmarshal's input typically has. It returns an ITree: building
+// color-correct nodes directly requires owning the node representation,
+// and only the immutable backend does.
+func LoadSorted(comparator utils.Comparator, iter func() (k interface{}, values []Value, found bool)) (ITree, error) {
+	if comparator == nil {
+		return nil, errors.New("comparator required")
+	}
+
+	var entries []pkg.Entry
+	for {
+		k, values, found := iter()
+		if !found {
+			break
+		}
+
+		entries = append(entries, pkg.Entry{Key: k, Values: values})
+	}
+
+	return itree{it: pkg.LoadSorted(pkg.Comparator(comparator), entries)}, nil
+}
@@ -0,0 +1,134 @@
+package redblacktree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/emirpasic/gods/utils"
+)
+
+func init() {
+	Register(Entity(""))
+	Register(0)
+}
+
+func TestMarshalBinaryRoundTrip(t *testing.T) {
+	tree, _ := New(utils.IntComparator)
+	tree.Put(1, Entity("A"))
+	tree.Put(1, Entity("B"))
+	tree.Put(2, Entity("C"))
+
+	var buf bytes.Buffer
+	if err := tree.Marshal(&buf, EncodingBinary); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Unmarshal(&buf, utils.IntComparator, EncodingBinary, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Println(loaded.Size())
+	fmt.Println(loaded.Get(1))
+	fmt.Println(loaded.Get(2))
+}
+
+func TestMarshalJSONRoundTrip(t *testing.T) {
+	// JSON keys are left as-is by Unmarshal (e.g. numbers decode as
+	// float64), so this demonstrates string keys, which round-trip as-is.
+	tree, _ := New(utils.StringComparator)
+	tree.Put("a", Entity("A"))
+	tree.Put("b", Entity("B"))
+
+	var buf bytes.Buffer
+	if err := tree.Marshal(&buf, EncodingJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Println(buf.String())
+
+	decodeValue := func(raw json.RawMessage) (Value, error) {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return Entity(s), nil
+	}
+
+	loaded, err := Unmarshal(&buf, utils.StringComparator, EncodingJSON, decodeValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Println(loaded.Size())
+	fmt.Println(loaded.Get("a"))
+	fmt.Println(loaded.Get("b"))
+}
+
+func TestLoadSorted(t *testing.T) {
+	entries := []struct {
+		key    int
+		values []Value
+	}{
+		{1, []Value{Entity("A")}},
+		{2, []Value{Entity("B")}},
+		{3, []Value{Entity("C")}},
+	}
+
+	i := 0
+	loaded, err := LoadSorted(utils.IntComparator, func() (interface{}, []Value, bool) {
+		if i >= len(entries) {
+			return nil, nil, false
+		}
+		e := entries[i]
+		i++
+		return e.key, e.values, true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Println(loaded.Size())
+	fmt.Println(loaded.Get(2))
+}
+
+// TestLoadSortedRemove loads trees of every size from 0 to 32, a range that
+// covers both the perfectly balanced sizes (1, 3, 7, 15, 31) and every size
+// in between, then removes every key from each. A loader that leaves the
+// tree in anything but a valid left-leaning red-black shape panics here,
+// since Remove is the operation that walks moveRedLeft/moveRedRight.
+func TestLoadSortedRemove(t *testing.T) {
+	for n := 0; n <= 32; n++ {
+		i := 0
+		loaded, err := LoadSorted(utils.IntComparator, func() (interface{}, []Value, bool) {
+			if i >= n {
+				return nil, nil, false
+			}
+			key := i
+			i++
+			return key, []Value{Entity(fmt.Sprint(key))}, true
+		})
+		if err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+
+		if loaded.Size() != n {
+			t.Fatalf("n=%d: Size() = %d", n, loaded.Size())
+		}
+
+		loaded = loaded.Put(n, Entity("extra"))
+
+		for key := 0; key <= n; key++ {
+			if _, found := loaded.Get(key); !found {
+				t.Fatalf("n=%d: key %d missing before removal", n, key)
+			}
+			loaded = loaded.Remove(key)
+		}
+
+		if !loaded.Empty() {
+			t.Fatalf("n=%d: tree not empty after removing every key", n)
+		}
+	}
+}
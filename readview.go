@@ -0,0 +1,231 @@
+package redblacktree
+
+import (
+	"sort"
+
+	"github.com/koketama/redblacktree/internal/pkg"
+
+	"github.com/emirpasic/gods/utils"
+)
+
+// ReadView is a point-in-time, read-only view of a Tree's contents that
+// needs no further locking: it is unaffected by Puts and Removes on the
+// Tree made after NewReadView returns. Use it instead of iterating a Tree
+// directly, which would otherwise race with concurrent writers.
+type ReadView interface {
+	Iterator() Iterator
+	ReverseIterator() Iterator
+	Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool)
+	Get(key interface{}) (values []Value, found bool)
+	Min() (key interface{}, values []Value)
+	Max() (key interface{}, values []Value)
+	// Close releases the view's backing data ahead of garbage collection.
+	// A ReadView is safe, if useless, to keep using after Close; Close just
+	// lets a long-lived view give its memory back as soon as it's done.
+	Close()
+}
+
+// ReadViewMode selects how NewReadView captures its snapshot.
+type ReadViewMode int
+
+const (
+	// ReadViewSnapshot builds the view on the immutable/COW backend by
+	// putting every value into a fresh ITree: O(n log n) time and
+	// allocation up front (O(n) of it retained), since the mutable tree's
+	// nodes aren't shared with it, but the result is a real balanced tree,
+	// so Get, Range and both iterators cost exactly what they would on an
+	// ITree. This is the default.
+	ReadViewSnapshot ReadViewMode = iota
+	// ReadViewCopy copies the tree's in-order (key, values) pairs into a
+	// flat slice under the read lock: O(n) time and space to build, less
+	// than ReadViewSnapshot's O(n log n). Get and Seek become a binary
+	// search over a slice rather than a tree descent, and the backing
+	// array is friendlier to the CPU cache for a full scan.
+	ReadViewCopy
+)
+
+// NewReadView captures a consistent, lock-free snapshot of t's current
+// contents. With no mode given it builds a ReadViewSnapshot.
+func (t *tree) NewReadView(mode ...ReadViewMode) ReadView {
+	m := ReadViewSnapshot
+	if len(mode) > 0 {
+		m = mode[0]
+	}
+
+	if m == ReadViewCopy {
+		return t.newCopyReadView()
+	}
+
+	return &snapshotReadView{it: t.snapshotITree()}
+}
+
+// snapshotReadView is a ReadView backed by a pkg.ITree: a pinned root
+// pointer into a copy-on-write tree.
+type snapshotReadView struct {
+	it pkg.ITree
+}
+
+func (v *snapshotReadView) Iterator() Iterator        { return v.it.Iterator() }
+func (v *snapshotReadView) ReverseIterator() Iterator { return v.it.ReverseIterator() }
+
+func (v *snapshotReadView) Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool) {
+	v.it.Range(lo, hi, inclusive, fn)
+}
+
+func (v *snapshotReadView) Get(key interface{}) (values []Value, found bool) {
+	return v.it.Get(key)
+}
+
+func (v *snapshotReadView) Min() (key interface{}, values []Value) { return v.it.Min() }
+func (v *snapshotReadView) Max() (key interface{}, values []Value) { return v.it.Max() }
+
+func (v *snapshotReadView) Close() {
+	v.it = pkg.ITree{}
+}
+
+// copyEntry is one (key, values) pair copied out of a tree for a
+// ReadViewCopy view.
+type copyEntry struct {
+	key    interface{}
+	values []Value
+}
+
+// copyReadView is a ReadView backed by a flat, key-ascending slice copied
+// out of a Tree under its read lock.
+type copyReadView struct {
+	entries    []copyEntry
+	comparator utils.Comparator
+}
+
+func (t *tree) newCopyReadView() *copyReadView {
+	t.RLock()
+	defer t.RUnlock()
+
+	entries := make([]copyEntry, 0, t.rbt.Size())
+	it := t.rbt.Iterator()
+	for it.Next() {
+		entries = append(entries, copyEntry{key: it.Key(), values: it.Values()})
+	}
+
+	return &copyReadView{entries: entries, comparator: t.comparator}
+}
+
+// ceiling returns the index of the first entry with key >= key, or
+// len(entries) if there is none.
+func (v *copyReadView) ceiling(key interface{}) int {
+	return sort.Search(len(v.entries), func(i int) bool {
+		return v.comparator(v.entries[i].key, key) >= 0
+	})
+}
+
+func (v *copyReadView) Get(key interface{}) (values []Value, found bool) {
+	i := v.ceiling(key)
+	if i < len(v.entries) && v.comparator(v.entries[i].key, key) == 0 {
+		return v.entries[i].values, true
+	}
+
+	return nil, false
+}
+
+func (v *copyReadView) Min() (key interface{}, values []Value) {
+	if len(v.entries) == 0 {
+		return nil, nil
+	}
+
+	e := v.entries[0]
+	return e.key, e.values
+}
+
+func (v *copyReadView) Max() (key interface{}, values []Value) {
+	if len(v.entries) == 0 {
+		return nil, nil
+	}
+
+	e := v.entries[len(v.entries)-1]
+	return e.key, e.values
+}
+
+func (v *copyReadView) Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool) {
+	for i := v.ceiling(lo); i < len(v.entries); i++ {
+		e := v.entries[i]
+		if !inclusive && v.comparator(e.key, lo) == 0 {
+			continue
+		}
+
+		cmp := v.comparator(e.key, hi)
+		if cmp > 0 || (cmp == 0 && !inclusive) {
+			return
+		}
+
+		if !fn(e.key, e.values) {
+			return
+		}
+	}
+}
+
+func (v *copyReadView) Iterator() Iterator {
+	return newSliceIterator(v.entries, v.comparator, false)
+}
+
+func (v *copyReadView) ReverseIterator() Iterator {
+	return newSliceIterator(v.entries, v.comparator, true)
+}
+
+func (v *copyReadView) Close() {
+	v.entries = nil
+}
+
+// sliceIterator is a stateful iterator over a copyReadView's entries, in
+// ascending or descending order.
+type sliceIterator struct {
+	entries    []copyEntry
+	comparator utils.Comparator
+	reverse    bool
+	idx        int
+}
+
+func newSliceIterator(entries []copyEntry, comparator utils.Comparator, reverse bool) *sliceIterator {
+	it := &sliceIterator{entries: entries, comparator: comparator, reverse: reverse}
+	if reverse {
+		it.idx = len(entries)
+	} else {
+		it.idx = -1
+	}
+
+	return it
+}
+
+func (it *sliceIterator) Next() bool {
+	if it.reverse {
+		it.idx--
+	} else {
+		it.idx++
+	}
+
+	return it.idx >= 0 && it.idx < len(it.entries)
+}
+
+// Seek positions the iterator so the next call to Next lands on the first
+// entry >= key for a forward iterator, or the last entry <= key for a
+// reverse one.
+func (it *sliceIterator) Seek(key interface{}) bool {
+	i := sort.Search(len(it.entries), func(i int) bool {
+		return it.comparator(it.entries[i].key, key) >= 0
+	})
+
+	if it.reverse {
+		if i < len(it.entries) && it.comparator(it.entries[i].key, key) == 0 {
+			it.idx = i + 1
+		} else {
+			it.idx = i
+		}
+
+		return it.idx-1 >= 0
+	}
+
+	it.idx = i - 1
+	return i < len(it.entries)
+}
+
+func (it *sliceIterator) Key() interface{} { return it.entries[it.idx].key }
+func (it *sliceIterator) Values() []Value  { return it.entries[it.idx].values }
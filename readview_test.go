@@ -0,0 +1,42 @@
+package redblacktree
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/emirpasic/gods/utils"
+)
+
+func TestReadView(t *testing.T) {
+	tree, _ := New(utils.IntComparator)
+
+	tree.Put(1, Entity("A"))
+	tree.Put(2, Entity("B"))
+	tree.Put(3, Entity("C"))
+	tree.Put(4, Entity("D"))
+	tree.Put(5, Entity("E"))
+
+	for _, mode := range []ReadViewMode{ReadViewSnapshot, ReadViewCopy} {
+		view := tree.NewReadView(mode)
+
+		fmt.Println(view.Min())
+		fmt.Println(view.Max())
+		fmt.Println(view.Get(3))
+
+		view.Range(2, 4, true, func(k interface{}, v []Value) bool {
+			fmt.Println(k, v)
+			return true
+		})
+
+		// Writes after the view was taken must not be visible through it.
+		tree.Put(6, Entity("F"))
+		tree.Remove(1)
+
+		reverse := view.ReverseIterator()
+		for reverse.Next() {
+			fmt.Println(reverse.Key(), reverse.Values())
+		}
+
+		view.Close()
+	}
+}
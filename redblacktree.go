@@ -1,6 +1,7 @@
 package redblacktree
 
 import (
+	"io"
 	"sync"
 
 	"github.com/koketama/redblacktree/internal/pkg"
@@ -25,29 +26,71 @@ type Tree interface {
 	PopMin() (key interface{}, values []Value)
 	Max() (key interface{}, values []Value)
 	PopMax() (key interface{}, values []Value)
+	Ceiling(key interface{}) (k interface{}, values []Value, found bool)
+	Floor(key interface{}) (k interface{}, values []Value, found bool)
+	Higher(key interface{}) (k interface{}, values []Value, found bool)
+	Lower(key interface{}) (k interface{}, values []Value, found bool)
+	Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool)
 	Topology() string
-	Iterator() Iterator
+	Snapshot() (ITree, error)
+	NewReadView(mode ...ReadViewMode) ReadView
+	Txn() *Txn
+	Marshal(w io.Writer, encoding Encoding) error
 }
 
-// Iterator a stateful iterator whose elements are key/value pairs.
+// Iterator a stateful iterator whose elements are key/value pairs. Returned
+// by ReadView, never directly by Tree: a Tree's own iteration must go
+// through a ReadView so the tree's write lock and the caller's traversal
+// can't race.
 type Iterator interface {
 	Next() bool
+	Seek(key interface{}) bool
 	Key() interface{}
 	Values() []Value
 }
 
 type tree struct {
 	sync.RWMutex
-	rbt *pkg.Tree
+	rbt        pkg.Backend
+	comparator utils.Comparator
 }
 
-// New create a thread safe red-black-tree based on github.com/emirpasic/gods/trees/redblacktree
-func New(comparator utils.Comparator) (Tree, error) {
+// Backend selects which balanced-tree implementation a Tree uses underneath
+// its thread-safety wrapper. Switching backends only requires changing the
+// constructor call: the rest of the Tree interface stays the same.
+type Backend int
+
+const (
+	// RedBlack backs the tree with github.com/emirpasic/gods's red-black
+	// tree. This is the default.
+	RedBlack Backend = iota
+	// AVL backs the tree with a from-scratch AVL tree: more strictly
+	// height-balanced than red-black, trading slower mutations for faster
+	// lookups, a good fit for read-heavy workloads like leaderboards.
+	AVL
+)
+
+// New create a thread safe tree, red-black by default. Pass a Backend to
+// use a different balanced-tree implementation underneath.
+func New(comparator utils.Comparator, backend ...Backend) (Tree, error) {
 	if comparator == nil {
 		return nil, errors.New("comparator required")
 	}
 
-	return &tree{rbt: pkg.NewWith(comparator)}, nil
+	b := RedBlack
+	if len(backend) > 0 {
+		b = backend[0]
+	}
+
+	var rbt pkg.Backend
+	switch b {
+	case AVL:
+		rbt = pkg.NewAVL(comparator)
+	default:
+		rbt = pkg.NewWith(comparator)
+	}
+
+	return &tree{rbt: rbt, comparator: comparator}, nil
 }
 
 func (t *tree) Put(key interface{}, value Value) {
@@ -108,7 +151,7 @@ func (t *tree) PopMin() (key interface{}, values []Value) {
 	t.Lock()
 	defer t.Unlock()
 
-	return t.rbt.PopLeft()
+	return pkg.PopLeft(t.rbt)
 }
 
 func (t *tree) Max() (key interface{}, values []Value) {
@@ -122,7 +165,65 @@ func (t *tree) PopMax() (key interface{}, values []Value) {
 	t.Lock()
 	defer t.Unlock()
 
-	return t.rbt.PopRight()
+	return pkg.PopRight(t.rbt)
+}
+
+func (t *tree) Ceiling(key interface{}) (k interface{}, values []Value, found bool) {
+	if key == nil {
+		return
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.rbt.Ceiling(key)
+}
+
+func (t *tree) Floor(key interface{}) (k interface{}, values []Value, found bool) {
+	if key == nil {
+		return
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.rbt.Floor(key)
+}
+
+func (t *tree) Higher(key interface{}) (k interface{}, values []Value, found bool) {
+	if key == nil {
+		return
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.rbt.Higher(key)
+}
+
+func (t *tree) Lower(key interface{}) (k interface{}, values []Value, found bool) {
+	if key == nil {
+		return
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.rbt.Lower(key)
+}
+
+// Range walks keys between lo and hi in ascending order, holding the tree's
+// read lock for the duration of the walk. fn must not call back into the
+// tree.
+func (t *tree) Range(lo, hi interface{}, inclusive bool, fn func(k interface{}, v []Value) bool) {
+	if lo == nil || hi == nil || fn == nil {
+		return
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	t.rbt.Range(lo, hi, inclusive, fn)
 }
 
 func (t *tree) Topology() string {
@@ -132,7 +233,31 @@ func (t *tree) Topology() string {
 	return t.rbt.String()
 }
 
-func (t *tree) Iterator() Iterator {
-	iterator := t.rbt.Iterator()
-	return &iterator
+// Snapshot returns a point-in-time, immutable view of the tree's current
+// contents, cheap enough to hand to a reader while writers keep going: it
+// needs no further locking once returned. Because this mutable tree's
+// backend doesn't share structure with the immutable one, building the
+// view puts every value into a new ITree, costing O(n log n) under the
+// read lock, rather than the O(1) of pinning a root pointer on a
+// copy-on-write backend.
+func (t *tree) Snapshot() (ITree, error) {
+	return itree{it: t.snapshotITree()}, nil
+}
+
+// snapshotITree builds a pkg.ITree holding every (key, values) pair
+// currently in t, under t's read lock. Snapshot and NewReadView's
+// ReadViewSnapshot mode both build on this.
+func (t *tree) snapshotITree() pkg.ITree {
+	t.RLock()
+	defer t.RUnlock()
+
+	txn := pkg.NewImmutable(pkg.Comparator(t.comparator)).Txn()
+	it := t.rbt.Iterator()
+	for it.Next() {
+		for _, v := range it.Values() {
+			txn.Put(it.Key(), v)
+		}
+	}
+
+	return txn.Commit()
 }
@@ -71,8 +71,68 @@ func TestIterator(t *testing.T) {
 
 	tree.Put(4, Entity("J"))
 
-	iterator := tree.Iterator()
+	view := tree.NewReadView()
+	defer view.Close()
+
+	iterator := view.Iterator()
 	for iterator.Next() {
 		fmt.Println(iterator.Key(), iterator.Values())
 	}
+
+	seeker := view.Iterator()
+	seeker.Seek(3)
+	for seeker.Next() {
+		fmt.Println(seeker.Key(), seeker.Values())
+	}
+
+	reverse := view.ReverseIterator()
+	for reverse.Next() {
+		fmt.Println(reverse.Key(), reverse.Values())
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	tree, _ := New(utils.IntComparator)
+
+	tree.Put(1, Entity("A"))
+	tree.Put(3, Entity("B"))
+	tree.Put(5, Entity("C"))
+	tree.Put(7, Entity("D"))
+
+	fmt.Println(tree.Floor(4))
+	fmt.Println(tree.Ceiling(4))
+	fmt.Println(tree.Lower(5))
+	fmt.Println(tree.Higher(5))
+
+	tree.Range(3, 7, true, func(k interface{}, v []Value) bool {
+		fmt.Println(k, v)
+		return true
+	})
+}
+
+func TestAVLBackend(t *testing.T) {
+	tree, _ := New(utils.IntComparator, AVL)
+
+	for i := 1; i <= 20; i++ {
+		tree.Put(i, Entity(fmt.Sprintf("v%d", i)))
+	}
+
+	fmt.Printf("size:%d\n%s", tree.Size(), tree.Topology())
+
+	fmt.Println(tree.Floor(10))
+	fmt.Println(tree.Ceiling(10))
+	fmt.Println(tree.Lower(10))
+	fmt.Println(tree.Higher(10))
+
+	view := tree.NewReadView(ReadViewCopy)
+	iterator := view.Iterator()
+	for iterator.Next() {
+		fmt.Println(iterator.Key(), iterator.Values())
+	}
+	view.Close()
+
+	for !tree.Empty() {
+		key, values := tree.PopMin()
+		fmt.Println(tree.Size(), key, values)
+	}
 }
@@ -0,0 +1,138 @@
+package redblacktree
+
+// Txn batches Put/Remove calls against a Tree and applies them as a single
+// critical section: the write lock is acquired once, in Commit, instead of
+// once per call. Get reflects the txn's own pending changes. Abort discards
+// everything staged so far without touching the tree.
+type Txn struct {
+	tree    *tree
+	ops     []txnOp
+	pending map[interface{}][]Value
+	removed map[interface{}]bool
+	order   []interface{}
+	touched map[interface{}]bool
+	notify  func(key interface{}, oldValues, newValues []Value)
+}
+
+type txnOp struct {
+	key    interface{}
+	value  Value
+	remove bool
+}
+
+// Txn opens a batch of Put/Remove calls against the tree.
+func (t *tree) Txn() *Txn {
+	return &Txn{
+		tree:    t,
+		pending: make(map[interface{}][]Value),
+		removed: make(map[interface{}]bool),
+		touched: make(map[interface{}]bool),
+	}
+}
+
+// Put stages value under key.
+func (tx *Txn) Put(key interface{}, value Value) {
+	if key == nil || value == nil {
+		return
+	}
+
+	values, _ := tx.Get(key)
+	for _, v := range values {
+		if v.ID() == value.ID() {
+			return
+		}
+	}
+
+	tx.pending[key] = append(append([]Value{}, values...), value)
+	delete(tx.removed, key)
+	tx.touch(key)
+	tx.ops = append(tx.ops, txnOp{key: key, value: value})
+}
+
+// Remove stages the removal of key and its values.
+func (tx *Txn) Remove(key interface{}) {
+	if key == nil {
+		return
+	}
+
+	delete(tx.pending, key)
+	tx.removed[key] = true
+	tx.touch(key)
+	tx.ops = append(tx.ops, txnOp{key: key, remove: true})
+}
+
+// Get returns what key would resolve to if Commit were called now: the
+// staged values if the txn has touched key, otherwise the tree's committed
+// values.
+func (tx *Txn) Get(key interface{}) (values []Value, found bool) {
+	if key == nil {
+		return nil, false
+	}
+
+	if tx.removed[key] {
+		return nil, false
+	}
+
+	if values, ok := tx.pending[key]; ok {
+		return values, true
+	}
+
+	return tx.tree.Get(key)
+}
+
+// Notify registers a hook called once per key touched by this txn when
+// Commit applies it, with the values the key held immediately before and
+// immediately after.
+func (tx *Txn) Notify(fn func(key interface{}, oldValues, newValues []Value)) {
+	tx.notify = fn
+}
+
+// Commit acquires the tree's write lock once and replays every staged
+// Put/Remove, in the order they were called, then invokes the Notify hook
+// (if any) once per touched key with its before/after values.
+func (tx *Txn) Commit() {
+	tx.tree.Lock()
+	defer tx.tree.Unlock()
+
+	oldByKey := make(map[interface{}][]Value, len(tx.order))
+	for _, key := range tx.order {
+		old, _ := tx.tree.rbt.Get(key)
+		oldByKey[key] = append([]Value{}, old...)
+	}
+
+	for _, op := range tx.ops {
+		if op.remove {
+			tx.tree.rbt.Remove(op.key)
+		} else {
+			tx.tree.rbt.Put(op.key, op.value)
+		}
+	}
+
+	if tx.notify == nil {
+		return
+	}
+
+	for _, key := range tx.order {
+		newValues, _ := tx.tree.rbt.Get(key)
+		tx.notify(key, oldByKey[key], newValues)
+	}
+}
+
+// Abort discards every staged Put/Remove. The tree is never touched until
+// Commit, so Abort is always a no-op on the tree itself.
+func (tx *Txn) Abort() {
+	tx.ops = nil
+	tx.pending = make(map[interface{}][]Value)
+	tx.removed = make(map[interface{}]bool)
+	tx.order = nil
+	tx.touched = make(map[interface{}]bool)
+}
+
+func (tx *Txn) touch(key interface{}) {
+	if tx.touched[key] {
+		return
+	}
+
+	tx.touched[key] = true
+	tx.order = append(tx.order, key)
+}
@@ -0,0 +1,43 @@
+package redblacktree
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/emirpasic/gods/utils"
+)
+
+func TestTxn(t *testing.T) {
+	tree, _ := New(utils.IntComparator)
+	tree.Put(1, Entity("A"))
+
+	txn := tree.Txn()
+	txn.Put(1, Entity("B"))
+	txn.Put(2, Entity("C"))
+	txn.Remove(1)
+
+	fmt.Println(txn.Get(1))
+	fmt.Println(txn.Get(2))
+	fmt.Println(tree.Get(1))
+
+	txn.Notify(func(key interface{}, oldValues, newValues []Value) {
+		fmt.Println("changed", key, oldValues, newValues)
+	})
+	txn.Commit()
+
+	fmt.Println(tree.Get(1))
+	fmt.Println(tree.Get(2))
+}
+
+func TestTxnAbort(t *testing.T) {
+	tree, _ := New(utils.IntComparator)
+	tree.Put(1, Entity("A"))
+
+	txn := tree.Txn()
+	txn.Put(2, Entity("B"))
+	txn.Abort()
+	txn.Commit()
+
+	fmt.Println(tree.Get(1))
+	fmt.Println(tree.Get(2))
+}